@@ -1,215 +1,166 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"github.com/34South/envr"
 	"io/ioutil"
 	"log"
-	"encoding/json"
-	"github.com/mikedonnici/pubmed"
-	"github.com/mikedonnici/elastic"
 	"os"
-	"strconv"
-	"github.com/pkg/errors"
+
+	"github.com/34South/envr"
+	goelastic "github.com/olivere/elastic/v7"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/8o8/articles/pubmedindex"
+	"github.com/8o8/articles/sink"
 )
 
-// Index maps to the JSON data specifying the index being created
+// indexName is the Elasticsearch index all queries are written to.
+const indexName = "articles"
+
+// pageSize is how many articles are fetched per EFetch request while paging through
+// a search's results.
+const pageSize = 500
+
+// Index maps to one entry of the JSON data specifying the searches to run.
 type Index struct {
 	Category string `json:"category"`
 	Term     string `json:"term"`
 	RelDate  int    `json:"reldate"`
-}
-
-type Article struct {
-	ID          string   `json:"id"`
-	URL         string   `json:"url"`
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Keywords    []string `json:"keywords"`
-	Categories  []string `json:"categories"`
-	PubTime     int64    `json:"pubTime"`
-	PubDate     string   `json:"pubDate"`
-	PubName     string   `json:"pubName"`
-	PubNameAbbr string   `json:"pubNameAbbr"`
-	PubPageRef  string   `json:"pubPageRef"`
-}
 
-func init() {
-	envr.New("articlesEnv", []string{
-		"ELASTIC_URL",
-		"ELASTIC_USER",
-		"ELASTIC_PASS",
-	}).Auto()
+	// Sink selects where matching articles are written: "elastic" (the default),
+	// "jsonl" or "stdout". "jsonl"/"stdout" let operators dry-run a query before
+	// touching the Elasticsearch cluster.
+	Sink string `json:"sink"`
 }
 
 func main() {
-
 	fmt.Println("Articler...")
 
-	e := elastic.NewClient(os.Getenv("ELASTIC_URL"), os.Getenv("ELASTIC_USER"), os.Getenv("ELASTIC_PASS"))
-	err := e.CheckOK()
-	if err != nil {
-		log.Fatalln(err)
-	}
-	fmt.Println("Connected to elastic search")
+	ctx := context.Background()
 
-	// Read in the config
 	xb, err := ioutil.ReadFile("indices.json")
 	if err != nil {
 		log.Fatalln("Could not read indices.json")
 	}
 
 	var indices []Index
-	err = json.Unmarshal(xb, &indices)
-	if err != nil {
+	if err := json.Unmarshal(xb, &indices); err != nil {
 		log.Fatalln("Could not unmarshal indices.json", err)
 	}
 
-	for _, v := range indices {
+	if needsElastic(indices) {
+		envr.New("articlesEnv", []string{
+			"ELASTIC_URL",
+			"ELASTIC_USER",
+			"ELASTIC_PASS",
+		}).Auto()
+	}
 
-		p := pubmed.NewSearch(v.Term)
-		p.BackDays = v.RelDate
-		err := p.Search()
-		if err != nil {
-			log.Fatalln(err)
-		}
+	client := pubmed.NewClient(os.Getenv("PUBMED_API_KEY"))
 
-		// post batches to elastic
-		batchSize := 500
-		for i := 0; i < p.ResultCount; i++ {
-			xa, err := p.Articles(i, batchSize)
-			if err != nil {
-				fmt.Println(err)
+	var es *goelastic.Client
+	sinks := map[string]sink.Sink{}
+	defer func() {
+		for _, sk := range sinks {
+			if err := sk.Close(); err != nil {
+				log.Println(err)
 			}
+		}
+	}()
 
-			fmt.Println("############################################################################################")
-			fmt.Println("Creating Batch", i, "-", i+batchSize)
-			body := ""
-
-			for _, a := range xa.Articles {
-
-				doc, err := mapArticle(a)
-				if err != nil {
-					continue
-				}
-
-				// Index the doc
-				body += fmt.Sprintf("{\"index\": {\"_id\": \"%v\"}}\n", a.ID)
-				body += fmt.Sprintf("%s\n", doc)
-
-				// Update categories in same doc
-				script := fmt.Sprintf("{\"source\": \"ctx._source.categories.add(params.category)\", \"lang\": \"painless\", \"params\": {\"category\": \"%s\"}}", v.Category)
-				body += fmt.Sprintf("{\"update\": {\"_id\": \"%v\"}}\n", a.ID)
-				body += fmt.Sprintf("{\"script\": %s}\n", script)
-				body += "\n"
-
-				//fmt.Println(body)
-				//os.Exit(0)
-			}
+	for _, v := range indices {
+		if v.Sink == "" {
+			v.Sink = "elastic"
+		}
 
-			_, err = e.Batch("articles", body)
+		sk, ok := sinks[v.Sink]
+		if !ok {
+			var err error
+			sk, es, err = buildSink(ctx, es, v.Sink)
 			if err != nil {
 				log.Fatalln(err)
 			}
+			sinks[v.Sink] = sk
+		}
 
-			i += batchSize
+		if err := search(ctx, client, v, sk); err != nil {
+			log.Fatalln(err)
 		}
 	}
-
-	//indices, err := e.Indices()
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//fmt.Println(indices)
-	//
-	////err = e.CreateIndex("NewIndex")
-	////if err != nil {
-	////	fmt.Println(err)
-	////	os.Exit(1)
-	////}
-	//
-	//indices, err = e.Indices()
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//fmt.Println(indices)
-	//
-	//newDoc := `{"title": "this is the way we roll", "description": "A short story"}`
-	//err = e.IndexDoc("articles", "123abc", newDoc)
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//
-	//xb, err := e.QueryDoc("articles", "123abc")
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//fmt.Println(string(xb))
-	//
-	////err = e.DeleteIndex("newindex")
-	////if err != nil {
-	////	fmt.Println(err)
-	////	os.Exit(1)
-	////}
-	//
-	//err = e.UpdateDoc("articles", "123abc", `{"author": "Mike Donnici"}`)
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//
-	//xb, err = e.QueryDoc("articles", "123abc")
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//fmt.Println(string(xb))
-	//
-	//err = e.DeleteDoc("articles", "123abc")
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//
-	//xb, err = e.QueryDoc("articles", "123abc")
-	//if err != nil {
-	//	fmt.Println(err)
-	//	os.Exit(1)
-	//}
-	//fmt.Println(string(xb))
-
 }
 
-// map a pubmed.Article to local Article, then returns it as a JSON string
-func mapArticle(a pubmed.Article) (string, error) {
+// needsElastic reports whether any index uses the "elastic" sink (the default for an
+// unset Sink), so a run that's entirely "jsonl"/"stdout" can skip the Elasticsearch
+// credential check altogether.
+func needsElastic(indices []Index) bool {
+	for _, v := range indices {
+		if v.Sink == "" || v.Sink == "elastic" {
+			return true
+		}
+	}
+	return false
+}
 
-	var at Article
+// search runs v's query, then streams matching articles page by page into sk.
+func search(ctx context.Context, client *pubmed.Client, v Index, sk sink.Sink) error {
+	result, err := client.ESearch(ctx, pubmed.SearchParams{Term: v.Term, BackDays: v.RelDate})
+	if err != nil {
+		return err
+	}
 
-	at.ID = strconv.Itoa(a.ID)
-	at.Title = a.Title
-	at.URL = a.URL
-	at.Keywords = a.Keywords
-	at.PubName = a.Journal
-	at.PubNameAbbr = a.JournalAbbrev
-	at.PubPageRef = a.Pages
-	at.PubTime = a.PubDate.Unix()
-	at.PubDate = a.PubDate.String()
+	fmt.Printf("%s: %d results\n", v.Category, result.Count)
 
-	if len(a.Abstract) > 0 {
-		at.Description = a.Abstract[0].Value
+	// Cursor works on any supported Go version; Batched is an opt-in convenience on
+	// top of it that needs Go 1.23's "iter" package, which this repo doesn't pin.
+	cursor := result.Cursor(ctx, client, pageSize)
+	for cursor.Next() {
+		article := cursor.Article()
+		if err := sk.Write(ctx, []pubmed.PubMedArticle{article}, []string{v.Category}); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
 	}
 
-	// Need this to be empty array, not null, else updating category won't work
-	at.Categories = []string{}
+	return sk.Flush(ctx)
+}
 
-	xb, err := json.Marshal(at)
-	if err != nil {
-		return "", errors.Wrap(err, "mapArticle")
-	}
+// buildSink constructs the sink for kind. For the "elastic" sink it lazily dials (and
+// EnsureIndexes) es on first use, returning the now-connected client so the caller can
+// reuse it for later indices -- indices.json entries that only use "jsonl"/"stdout"
+// sinks never need a reachable Elasticsearch cluster at all.
+func buildSink(ctx context.Context, es *goelastic.Client, kind string) (sink.Sink, *goelastic.Client, error) {
+	switch kind {
+	case "jsonl":
+		return sink.NewJSONLinesSink(os.Stdout), es, nil
+	case "stdout":
+		return sink.NewStdoutSink(os.Stdout), es, nil
+	case "elastic", "":
+		if es == nil {
+			var err error
+			es, err = goelastic.NewClient(
+				goelastic.SetURL(os.Getenv("ELASTIC_URL")),
+				goelastic.SetBasicAuth(os.Getenv("ELASTIC_USER"), os.Getenv("ELASTIC_PASS")),
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			fmt.Println("Connected to elastic search")
 
-	return string(xb), nil
+			if err := pubmedindex.EnsureIndex(ctx, es, indexName, pubmedindex.IndexMapping); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		indexer, err := pubmedindex.NewBulkIndexer(ctx, es, indexName)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sink.NewElasticSink(indexer), es, nil
+	default:
+		return nil, nil, fmt.Errorf("main: unknown sink %q", kind)
+	}
 }
@@ -0,0 +1,37 @@
+package pubmed
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoffer returns how long to wait before the nth retry attempt (1-indexed).
+type Backoffer interface {
+	Duration(attempt int) time.Duration
+}
+
+// ExponentialBackoff doubles the wait on each attempt up to Max, with jitter added so
+// many clients backing off at once don't retry against NCBI in lockstep.
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// NewExponentialBackoff returns an ExponentialBackoff starting at base and capped at
+// max.
+func NewExponentialBackoff(base, max time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{Base: base, Max: max}
+}
+
+// Duration returns the wait for the given attempt, half of it fixed and half jittered.
+func (b *ExponentialBackoff) Duration(attempt int) time.Duration {
+	d := time.Duration(float64(b.Base) * math.Pow(2, float64(attempt-1)))
+	if d > b.Max {
+		d = b.Max
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+
+	return d/2 + jitter
+}
@@ -0,0 +1,39 @@
+package pubmed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/matryer/is"
+)
+
+func TestExponentialBackoffCapsAtMax(t *testing.T) {
+	is := is.New(t)
+
+	b := pubmed.NewExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := b.Duration(attempt)
+		is.True(d >= 0)
+		is.True(d <= 50*time.Millisecond) // Expected every attempt to stay within Max
+	}
+}
+
+func TestExponentialBackoffGrows(t *testing.T) {
+	is := is.New(t)
+
+	b := pubmed.NewExponentialBackoff(10*time.Millisecond, time.Hour)
+
+	// With jitter, a single sample is noisy, so compare averages over many samples.
+	avg := func(attempt int) time.Duration {
+		var total time.Duration
+		const samples = 200
+		for i := 0; i < samples; i++ {
+			total += b.Duration(attempt)
+		}
+		return total / samples
+	}
+
+	is.True(avg(3) > avg(1)) // Expected later attempts to back off longer on average
+}
@@ -0,0 +1,121 @@
+package pubmed
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Cache is a pluggable store for previously-fetched PubMedArticle records, keyed by
+// PMID, so repeated EFetch calls against overlapping queries don't re-hit NCBI.
+type Cache interface {
+	Get(pmid string) (PubMedArticle, bool)
+	Put(a PubMedArticle, ttl time.Duration)
+	Stats() CacheStats
+}
+
+// CacheStats reports cumulative cache activity for observability.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+type lruEntry struct {
+	pmid      string
+	article   PubMedArticle
+	expiresAt time.Time
+}
+
+// LRUCache is an in-memory, size-bounded Cache with per-entry TTL expiry.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	index    map[string]*list.Element
+	stats    CacheStats
+}
+
+// NewLRUCache returns an LRUCache holding at most capacity entries. A capacity <= 0
+// means unbounded.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached article for pmid, if present and unexpired.
+func (c *LRUCache) Get(pmid string) (PubMedArticle, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.index[pmid]
+	if !ok {
+		c.stats.Misses++
+		return PubMedArticle{}, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		c.stats.Misses++
+		return PubMedArticle{}, false
+	}
+
+	c.ll.MoveToFront(el)
+	c.stats.Hits++
+
+	return entry.article, true
+}
+
+// Put stores a, keyed by its PMID, expiring it after ttl (0 means it never expires).
+func (c *LRUCache) Put(a PubMedArticle, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pmid := strconv.Itoa(a.ID)
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.index[pmid]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.article = a
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&lruEntry{pmid: pmid, article: a, expiresAt: expiresAt})
+	c.index[pmid] = el
+
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		c.evictOldest()
+	}
+}
+
+// Stats returns a snapshot of the cache's cumulative hit/miss/eviction counts.
+func (c *LRUCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *LRUCache) evictOldest() {
+	el := c.ll.Back()
+	if el == nil {
+		return
+	}
+	c.removeElement(el)
+	c.stats.Evictions++
+}
+
+func (c *LRUCache) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.index, el.Value.(*lruEntry).pmid)
+}
@@ -0,0 +1,116 @@
+package pubmed
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/pkg/errors"
+)
+
+var articlesBucket = []byte("pubmed_articles")
+
+type diskCacheEntry struct {
+	Article   PubMedArticle `json:"article"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// DiskCache is a Cache backed by a BoltDB file, so articles fetched in one run are
+// still available on the next one rather than being re-fetched from NCBI.
+type DiskCache struct {
+	db *bolt.DB
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// NewDiskCache opens (creating if necessary) a BoltDB file at path for use as a
+// Cache.
+func NewDiskCache(path string) (*DiskCache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "NewDiskCache")
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(articlesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "NewDiskCache")
+	}
+
+	return &DiskCache{db: db}, nil
+}
+
+// Get returns the cached article for pmid, if present and unexpired.
+func (c *DiskCache) Get(pmid string) (PubMedArticle, bool) {
+	var entry diskCacheEntry
+	var found bool
+
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(articlesBucket).Get([]byte(pmid))
+		if v == nil {
+			return nil
+		}
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+
+	if !found {
+		atomic.AddInt64(&c.misses, 1)
+		return PubMedArticle{}, false
+	}
+
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+		atomic.AddInt64(&c.misses, 1)
+		atomic.AddInt64(&c.evictions, 1)
+		_ = c.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(articlesBucket).Delete([]byte(pmid))
+		})
+		return PubMedArticle{}, false
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+
+	return entry.Article, true
+}
+
+// Put stores a, keyed by its PMID, expiring it after ttl (0 means it never expires).
+func (c *DiskCache) Put(a PubMedArticle, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	xb, err := json.Marshal(diskCacheEntry{Article: a, ExpiresAt: expiresAt})
+	if err != nil {
+		return
+	}
+
+	_ = c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(articlesBucket).Put([]byte(strconv.Itoa(a.ID)), xb)
+	})
+}
+
+// Stats returns a snapshot of the cache's cumulative hit/miss/eviction counts.
+func (c *DiskCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:      atomic.LoadInt64(&c.hits),
+		Misses:    atomic.LoadInt64(&c.misses),
+		Evictions: atomic.LoadInt64(&c.evictions),
+	}
+}
+
+// Close releases the underlying BoltDB file handle.
+func (c *DiskCache) Close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,66 @@
+package pubmed_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/matryer/is"
+)
+
+func TestDiskCacheGetPut(t *testing.T) {
+	is := is.New(t)
+
+	c, err := pubmed.NewDiskCache(filepath.Join(t.TempDir(), "cache.db"))
+	is.NoErr(err)
+	defer c.Close()
+
+	c.Put(pubmed.PubMedArticle{ID: 1, Title: "one"}, 0)
+
+	got, ok := c.Get("1")
+	is.True(ok) // Expected a cache hit
+	is.Equal(got.Title, "one")
+
+	_, ok = c.Get("2")
+	is.True(!ok) // Expected a cache miss for an id never Put
+
+	stats := c.Stats()
+	is.Equal(stats.Hits, int64(1))
+	is.Equal(stats.Misses, int64(1))
+}
+
+func TestDiskCacheExpiry(t *testing.T) {
+	is := is.New(t)
+
+	c, err := pubmed.NewDiskCache(filepath.Join(t.TempDir(), "cache.db"))
+	is.NoErr(err)
+	defer c.Close()
+
+	c.Put(pubmed.PubMedArticle{ID: 1}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := c.Get("1")
+	is.True(!ok) // Expected the entry to have expired
+
+	is.Equal(c.Stats().Evictions, int64(1))
+}
+
+func TestDiskCachePersistsAcrossReopen(t *testing.T) {
+	is := is.New(t)
+
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	c, err := pubmed.NewDiskCache(path)
+	is.NoErr(err)
+	c.Put(pubmed.PubMedArticle{ID: 1, Title: "one"}, 0)
+	is.NoErr(c.Close())
+
+	reopened, err := pubmed.NewDiskCache(path)
+	is.NoErr(err)
+	defer reopened.Close()
+
+	got, ok := reopened.Get("1")
+	is.True(ok) // Expected the entry to survive a reopen of the same file
+	is.Equal(got.Title, "one")
+}
@@ -0,0 +1,55 @@
+package pubmed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/matryer/is"
+)
+
+func TestLRUCacheGetPut(t *testing.T) {
+	is := is.New(t)
+	c := pubmed.NewLRUCache(0)
+
+	c.Put(pubmed.PubMedArticle{ID: 1, Title: "one"}, 0)
+
+	got, ok := c.Get("1")
+	is.True(ok)                 // Expected a cache hit
+	is.Equal(got.Title, "one")
+
+	_, ok = c.Get("2")
+	is.True(!ok) // Expected a cache miss for an id never Put
+
+	stats := c.Stats()
+	is.Equal(stats.Hits, int64(1))
+	is.Equal(stats.Misses, int64(1))
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	is := is.New(t)
+	c := pubmed.NewLRUCache(2)
+
+	c.Put(pubmed.PubMedArticle{ID: 1}, 0)
+	c.Put(pubmed.PubMedArticle{ID: 2}, 0)
+	c.Put(pubmed.PubMedArticle{ID: 3}, 0) // capacity 2: evicts 1
+
+	_, ok := c.Get("1")
+	is.True(!ok) // Expected id 1 to have been evicted
+
+	_, ok = c.Get("2")
+	is.True(ok) // Expected id 2 to still be cached
+
+	is.Equal(c.Stats().Evictions, int64(1))
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	is := is.New(t)
+	c := pubmed.NewLRUCache(0)
+
+	c.Put(pubmed.PubMedArticle{ID: 1}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := c.Get("1")
+	is.True(!ok) // Expected the entry to have expired
+}
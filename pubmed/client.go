@@ -0,0 +1,495 @@
+package pubmed
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBaseURL = "https://eutils.ncbi.nlm.nih.gov/entrez/eutils"
+
+	// postIDThreshold is the id-list size above which NCBI asks callers to switch
+	// from GET to POST. Ref: https://www.ncbi.nlm.nih.gov/books/NBK25497/
+	postIDThreshold = 200
+)
+
+// Client talks to the NCBI EUtilities API: ESearch, ESummary, EFetch, ELink and
+// EInfo. It rate-limits and retries requests per NCBI's published guidance, and
+// shares a single *http.Client across all calls rather than building one per request.
+type Client struct {
+	BaseURL    string
+	UserAgent  string
+	APIKey     string
+	Tool       string
+	Email      string
+	HTTPClient *http.Client
+
+	// Cache, if set, is consulted by EFetch before going to the network, and
+	// populated with whatever it had to fetch.
+	Cache    Cache
+	CacheTTL time.Duration
+
+	limiter    *rateLimiter
+	maxRetries int
+	backoff    Backoffer
+}
+
+// ClientOption configures a Client built with NewClient.
+type ClientOption func(*Client)
+
+// WithRateLimit overrides the client's default requests-per-second limit.
+func WithRateLimit(rps int) ClientOption {
+	return func(c *Client) { c.limiter = newRateLimiter(rps) }
+}
+
+// WithMaxRetries sets how many times a request is retried after a 429, 5xx, or
+// network error.
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// WithBackoff overrides the backoff strategy used between retries.
+func WithBackoff(b Backoffer) ClientOption {
+	return func(c *Client) { c.backoff = b }
+}
+
+// WithHTTPClient overrides the *http.Client used for all requests.
+func WithHTTPClient(h *http.Client) ClientOption {
+	return func(c *Client) { c.HTTPClient = h }
+}
+
+// WithCache attaches a Cache that EFetch consults before hitting the network, with
+// entries kept for ttl (0 means entries never expire).
+func WithCache(c Cache, ttl time.Duration) ClientOption {
+	return func(cl *Client) {
+		cl.Cache = c
+		cl.CacheTTL = ttl
+	}
+}
+
+// NewClient returns a Client configured with NCBI's published rate limits: 3
+// requests/sec without an apiKey, 10/sec with one. Pass options to override any
+// default.
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	rps := 3
+	if apiKey != "" {
+		rps = 10
+	}
+
+	c := &Client{
+		BaseURL:    defaultBaseURL,
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 90 * time.Second},
+		limiter:    newRateLimiter(rps),
+		maxRetries: 3,
+		backoff:    NewExponentialBackoff(500*time.Millisecond, 30*time.Second),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// SearchParams configures an ESearch request.
+type SearchParams struct {
+	DB       string // defaults to "pubmed"
+	Term     string
+	BackDays int
+	RetStart int
+	RetMax   int
+}
+
+// SearchResult is an ESearch response: the matching PMIDs (when within RetMax), plus
+// the QueryKey/WebEnv needed to page through the full result set via the history
+// server.
+type SearchResult struct {
+	Count    int
+	IDList   []string
+	QueryKey string
+	WebEnv   string
+}
+
+// ESearch runs an ESearch query against PubMed and returns the total hit count along
+// with the QueryKey/WebEnv needed to retrieve the rest via EFetch's history mode.
+func (c *Client) ESearch(ctx context.Context, p SearchParams) (*SearchResult, error) {
+	db := p.DB
+	if db == "" {
+		db = "pubmed"
+	}
+
+	form := url.Values{}
+	form.Set("db", db)
+	form.Set("term", p.Term)
+	form.Set("retmode", "json")
+	form.Set("usehistory", "y")
+	if p.BackDays > 0 {
+		form.Set("reldate", strconv.Itoa(p.BackDays))
+		form.Set("datetype", "pdat")
+	}
+	if p.RetStart > 0 {
+		form.Set("retstart", strconv.Itoa(p.RetStart))
+	}
+	if p.RetMax > 0 {
+		form.Set("retmax", strconv.Itoa(p.RetMax))
+	}
+
+	body, err := c.doRequest(ctx, http.MethodGet, c.endpoint("esearch.fcgi"), form)
+	if err != nil {
+		return nil, errors.Wrap(err, "ESearch")
+	}
+
+	var r struct {
+		Result struct {
+			Count    string   `json:"count"`
+			QueryKey string   `json:"querykey"`
+			WebEnv   string   `json:"webenv"`
+			IDList   []string `json:"idlist"`
+		} `json:"esearchresult"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, errors.Wrap(err, "ESearch: unmarshal")
+	}
+
+	count, err := strconv.Atoi(r.Result.Count)
+	if err != nil {
+		return nil, errors.Wrap(err, "ESearch: parse count")
+	}
+
+	return &SearchResult{
+		Count:    count,
+		IDList:   r.Result.IDList,
+		QueryKey: r.Result.QueryKey,
+		WebEnv:   r.Result.WebEnv,
+	}, nil
+}
+
+// ESummary fetches document summaries for the given PubMed IDs, returning the raw
+// per-document JSON keyed by PMID so callers can unmarshal into whatever shape they
+// need rather than this package guessing at ESummary's loosely-typed fields.
+func (c *Client) ESummary(ctx context.Context, ids ...string) (map[string]json.RawMessage, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	form := url.Values{}
+	form.Set("db", "pubmed")
+	form.Set("retmode", "json")
+	form.Set("id", strings.Join(ids, ","))
+
+	method := http.MethodGet
+	if len(ids) > postIDThreshold {
+		method = http.MethodPost
+	}
+
+	body, err := c.doRequest(ctx, method, c.endpoint("esummary.fcgi"), form)
+	if err != nil {
+		return nil, errors.Wrap(err, "ESummary")
+	}
+
+	var r struct {
+		Result map[string]json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, errors.Wrap(err, "ESummary: unmarshal")
+	}
+
+	delete(r.Result, "uids")
+
+	return r.Result, nil
+}
+
+// EFetch retrieves full article records for the given PubMed IDs, switching
+// automatically from GET to POST above NCBI's documented 200-id threshold. If the
+// Client has a Cache configured, cached articles are served without a network call
+// and only the cache misses are fetched and stored back.
+func (c *Client) EFetch(ctx context.Context, ids ...string) ([]PubMedArticle, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var articles []PubMedArticle
+	missing := ids
+
+	if c.Cache != nil {
+		missing = missing[:0]
+		for _, id := range ids {
+			if a, ok := c.Cache.Get(id); ok {
+				articles = append(articles, a)
+				continue
+			}
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return articles, nil
+	}
+
+	fetched, err := c.efetchIDs(ctx, missing)
+	if err != nil {
+		return nil, errors.Wrap(err, "EFetch")
+	}
+
+	if c.Cache != nil {
+		for _, a := range fetched {
+			c.Cache.Put(a, c.CacheTTL)
+		}
+	}
+
+	return append(articles, fetched...), nil
+}
+
+func (c *Client) efetchIDs(ctx context.Context, ids []string) ([]PubMedArticle, error) {
+	form := url.Values{}
+	form.Set("db", "pubmed")
+	form.Set("rettype", "abstract")
+	form.Set("retmode", "xml")
+	form.Set("id", strings.Join(ids, ","))
+
+	method := http.MethodGet
+	if len(ids) > postIDThreshold {
+		method = http.MethodPost
+	}
+
+	return c.efetch(ctx, method, form)
+}
+
+// esearchHistoryIDs returns the PMIDs in the page [retstart, retstart+retmax) of an
+// already-run ESearch, by replaying its WebEnv/QueryKey rather than the original term.
+// This is a cheap JSON call (no article bodies), and it's what lets ArticleCursor and
+// Batched fetch a page's articles by id through EFetch -- and therefore through
+// EFetch's Cache -- instead of always pulling the full page over the wire.
+func (c *Client) esearchHistoryIDs(ctx context.Context, webEnv, queryKey string, retstart, retmax int) ([]string, error) {
+	form := url.Values{}
+	form.Set("db", "pubmed")
+	form.Set("retmode", "json")
+	form.Set("WebEnv", webEnv)
+	form.Set("query_key", queryKey)
+	form.Set("retstart", strconv.Itoa(retstart))
+	form.Set("retmax", strconv.Itoa(retmax))
+
+	body, err := c.doRequest(ctx, http.MethodGet, c.endpoint("esearch.fcgi"), form)
+	if err != nil {
+		return nil, errors.Wrap(err, "esearchHistoryIDs")
+	}
+
+	var r struct {
+		Result struct {
+			IDList []string `json:"idlist"`
+		} `json:"esearchresult"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, errors.Wrap(err, "esearchHistoryIDs: unmarshal")
+	}
+
+	return r.Result.IDList, nil
+}
+
+func (c *Client) efetch(ctx context.Context, method string, form url.Values) ([]PubMedArticle, error) {
+	body, err := c.doRequest(ctx, method, c.endpoint("efetch.fcgi"), form)
+	if err != nil {
+		return nil, err
+	}
+
+	var set PubMedArticleSet
+	if err := xml.Unmarshal(body, &set); err != nil {
+		return nil, errors.Wrap(err, "efetch: unmarshal PubMedArticleSet")
+	}
+
+	return set.Articles, nil
+}
+
+// LinkParams configures an ELink request, e.g. finding articles related to or citing
+// a given set of PMIDs.
+type LinkParams struct {
+	DBFrom   string
+	DB       string
+	LinkName string
+	IDs      []string
+}
+
+// LinkSet is the simplified result of an ELink request: for one source id, the set of
+// linked ids found under LinkName.
+type LinkSet struct {
+	ID      string
+	LinkIDs []string
+}
+
+// ELink finds records linked to p.IDs, e.g. related articles or cited-by records,
+// depending on p.LinkName.
+func (c *Client) ELink(ctx context.Context, p LinkParams) ([]LinkSet, error) {
+	form := url.Values{}
+	form.Set("dbfrom", p.DBFrom)
+	form.Set("db", p.DB)
+	form.Set("retmode", "json")
+	if p.LinkName != "" {
+		form.Set("linkname", p.LinkName)
+	}
+	for _, id := range p.IDs {
+		form.Add("id", id)
+	}
+
+	body, err := c.doRequest(ctx, http.MethodGet, c.endpoint("elink.fcgi"), form)
+	if err != nil {
+		return nil, errors.Wrap(err, "ELink")
+	}
+
+	var r struct {
+		LinkSets []struct {
+			IDs       []string `json:"ids"`
+			LinkSetDb []struct {
+				LinkName string   `json:"linkname"`
+				Links    []string `json:"links"`
+			} `json:"linksetdbs"`
+		} `json:"linksets"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, errors.Wrap(err, "ELink: unmarshal")
+	}
+
+	var out []LinkSet
+	for _, ls := range r.LinkSets {
+		if len(ls.IDs) == 0 {
+			continue
+		}
+
+		var linked []string
+		for _, db := range ls.LinkSetDb {
+			linked = append(linked, db.Links...)
+		}
+
+		out = append(out, LinkSet{ID: ls.IDs[0], LinkIDs: linked})
+	}
+
+	return out, nil
+}
+
+// EInfo returns the raw JSON describing db's indexed fields and available ELink
+// names. Pass an empty db to list all available databases instead.
+func (c *Client) EInfo(ctx context.Context, db string) ([]byte, error) {
+	form := url.Values{}
+	form.Set("retmode", "json")
+	if db != "" {
+		form.Set("db", db)
+	}
+
+	body, err := c.doRequest(ctx, http.MethodGet, c.endpoint("einfo.fcgi"), form)
+	if err != nil {
+		return nil, errors.Wrap(err, "EInfo")
+	}
+
+	return body, nil
+}
+
+func (c *Client) endpoint(name string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = defaultBaseURL
+	}
+	return strings.TrimRight(base, "/") + "/" + name
+}
+
+// doRequest waits for the rate limiter, then performs method against rawURL with form
+// as either the query string (GET) or the body (POST), retrying on 429/5xx/network
+// errors per c.maxRetries and c.backoff.
+func (c *Client) doRequest(ctx context.Context, method, rawURL string, form url.Values) ([]byte, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, c.backoff.Duration(attempt)); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := c.newRequest(ctx, method, rawURL, form)
+		if err != nil {
+			return nil, errors.Wrap(err, "doRequest")
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = errors.Errorf("eutils: %s returned %s", rawURL, resp.Status)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("eutils: %s returned %s", rawURL, resp.Status)
+		}
+
+		return body, nil
+	}
+
+	return nil, errors.Wrap(lastErr, "doRequest: exhausted retries")
+}
+
+func (c *Client) newRequest(ctx context.Context, method, rawURL string, form url.Values) (*http.Request, error) {
+	if c.APIKey != "" {
+		form.Set("api_key", c.APIKey)
+	}
+	if c.Tool != "" {
+		form.Set("tool", c.Tool)
+	}
+	if c.Email != "" {
+		form.Set("email", c.Email)
+	}
+
+	var req *http.Request
+	var err error
+
+	if method == http.MethodGet {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, rawURL+"?"+form.Encode(), nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	return req, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,140 @@
+package pubmed_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/matryer/is"
+)
+
+func articleXML(id int, title string) string {
+	return fmt.Sprintf(`<PubmedArticle><MedlineCitation><PMID>%d</PMID><Article><ArticleTitle>%s</ArticleTitle></Article></MedlineCitation></PubmedArticle>`, id, title)
+}
+
+func TestClientRetriesOn500ThenSucceeds(t *testing.T) {
+	is := is.New(t)
+
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`<PubmedArticleSet>` + articleXML(1, "Eventually OK") + `</PubmedArticleSet>`))
+	}))
+	defer srv.Close()
+
+	c := pubmed.NewClient("",
+		pubmed.WithRateLimit(1000),
+		pubmed.WithMaxRetries(3),
+		pubmed.WithBackoff(pubmed.NewExponentialBackoff(time.Millisecond, 5*time.Millisecond)),
+	)
+	c.BaseURL = srv.URL
+
+	articles, err := c.EFetch(context.Background(), "1")
+	is.NoErr(err)
+	is.Equal(len(articles), 1)
+	is.Equal(articles[0].Title, "Eventually OK")
+	is.Equal(int(atomic.LoadInt32(&attempts)), 3)
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	is := is.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := pubmed.NewClient("",
+		pubmed.WithRateLimit(1000),
+		pubmed.WithMaxRetries(2),
+		pubmed.WithBackoff(pubmed.NewExponentialBackoff(time.Millisecond, 5*time.Millisecond)),
+	)
+	c.BaseURL = srv.URL
+
+	_, err := c.EFetch(context.Background(), "1")
+	is.True(err != nil) // Expected EFetch to fail once retries are exhausted
+}
+
+func TestClientRateLimitsRequests(t *testing.T) {
+	is := is.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"esearchresult":{"count":"0","idlist":[]}}`))
+	}))
+	defer srv.Close()
+
+	c := pubmed.NewClient("", pubmed.WithRateLimit(5)) // 5 rps => 200ms between requests
+	c.BaseURL = srv.URL
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.ESearch(context.Background(), pubmed.SearchParams{Term: "x"})
+		is.NoErr(err)
+	}
+
+	is.True(time.Since(start) >= 400*time.Millisecond) // Expected two intervals' worth of spacing
+}
+
+func TestEFetchServesFromCacheWithoutHittingNetwork(t *testing.T) {
+	is := is.New(t)
+
+	var requests int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		r.ParseForm()
+		ids := strings.Split(r.Form.Get("id"), ",")
+
+		var body strings.Builder
+		body.WriteString(`<PubmedArticleSet>`)
+		for _, id := range ids {
+			body.WriteString(articleXML(atoiOrZero(id), "Title "+id))
+		}
+		body.WriteString(`</PubmedArticleSet>`)
+
+		w.Write([]byte(body.String()))
+	}))
+	defer srv.Close()
+
+	c := pubmed.NewClient("",
+		pubmed.WithRateLimit(1000),
+		pubmed.WithCache(pubmed.NewLRUCache(0), 0),
+	)
+	c.BaseURL = srv.URL
+
+	first, err := c.EFetch(context.Background(), "1", "2")
+	is.NoErr(err)
+	is.Equal(len(first), 2)
+	is.Equal(int(atomic.LoadInt32(&requests)), 1)
+
+	// id 2 is already cached, so only id 3 should require a network round trip.
+	second, err := c.EFetch(context.Background(), "2", "3")
+	is.NoErr(err)
+	is.Equal(len(second), 2)
+	is.Equal(int(atomic.LoadInt32(&requests)), 2)
+
+	stats := c.Cache.Stats()
+	is.Equal(stats.Hits, int64(1))
+}
+
+func atoiOrZero(s string) int {
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
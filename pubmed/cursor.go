@@ -0,0 +1,105 @@
+package pubmed
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const defaultCursorPageSize = 500
+
+// ArticleCursor pages through an ESearch result set using the WebEnv/QueryKey history
+// server, fetching one page of PMIDs at a time and resolving them through EFetch --
+// and therefore through EFetch's Cache -- so callers never have to materialise the
+// full id list (or the full article set) in memory.
+type ArticleCursor struct {
+	client   *Client
+	webEnv   string
+	queryKey string
+	total    int
+	pageSize int
+	ctx      context.Context
+
+	page    []PubMedArticle
+	pos     int
+	fetched int
+	err     error
+}
+
+// Cursor returns an ArticleCursor over s's results, fetching pageSize articles per
+// request against the history server. A pageSize <= 0 uses a sensible default.
+func (s *SearchResult) Cursor(ctx context.Context, c *Client, pageSize int) *ArticleCursor {
+	if pageSize <= 0 {
+		pageSize = defaultCursorPageSize
+	}
+
+	return &ArticleCursor{
+		client:   c,
+		webEnv:   s.WebEnv,
+		queryKey: s.QueryKey,
+		total:    s.Count,
+		pageSize: pageSize,
+		ctx:      ctx,
+		pos:      -1,
+	}
+}
+
+// Next advances the cursor, fetching the next page from NCBI once the current page is
+// exhausted. It returns false once the result set is exhausted or an error occurs;
+// callers should check Err() after the loop ends.
+func (a *ArticleCursor) Next() bool {
+	if a.err != nil {
+		return false
+	}
+
+	a.pos++
+	if a.pos < len(a.page) {
+		return true
+	}
+
+	if a.fetched >= a.total {
+		return false
+	}
+
+	articles, err := fetchHistoryPage(a.ctx, a.client, a.webEnv, a.queryKey, a.fetched, a.pageSize)
+	if err != nil {
+		a.err = errors.Wrap(err, "ArticleCursor.Next")
+		return false
+	}
+	if len(articles) == 0 {
+		return false
+	}
+
+	a.page = articles
+	a.fetched += len(articles)
+	a.pos = 0
+
+	return true
+}
+
+// Article returns the article at the cursor's current position. It must only be
+// called after a call to Next that returned true.
+func (a *ArticleCursor) Article() PubMedArticle {
+	return a.page[a.pos]
+}
+
+// Err returns the first error encountered while advancing the cursor, if any.
+func (a *ArticleCursor) Err() error {
+	return a.err
+}
+
+// fetchHistoryPage resolves one page of a history-mode search to full articles: it
+// pages the PMIDs off the history server, then fetches them via EFetch so any
+// articles already in c.Cache (e.g. from an earlier, overlapping search) are served
+// without a network call.
+func fetchHistoryPage(ctx context.Context, c *Client, webEnv, queryKey string, retstart, pageSize int) ([]PubMedArticle, error) {
+	ids, err := c.esearchHistoryIDs(ctx, webEnv, queryKey, retstart, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return c.EFetch(ctx, ids...)
+}
@@ -0,0 +1,45 @@
+//go:build go1.23
+
+package pubmed
+
+import (
+	"context"
+	"iter"
+
+	"github.com/pkg/errors"
+)
+
+// Batched ranges over s's results pageSize articles at a time, for Go 1.23
+// range-over-func consumers that want to batch downstream work (e.g. bulk indexing)
+// rather than handle one article at a time. A pageSize <= 0 uses a sensible default.
+//
+// This is an opt-in convenience on top of Cursor -- nothing in this module's own
+// production path depends on it, since the stdlib "iter" package requires Go 1.23+
+// and this module's go.mod only requires Go 1.21. It lives behind a go1.23 build tag
+// so the rest of the package still builds on older toolchains.
+func (s *SearchResult) Batched(ctx context.Context, c *Client, pageSize int) iter.Seq2[[]PubMedArticle, error] {
+	if pageSize <= 0 {
+		pageSize = defaultCursorPageSize
+	}
+
+	return func(yield func([]PubMedArticle, error) bool) {
+		fetched := 0
+
+		for fetched < s.Count {
+			articles, err := fetchHistoryPage(ctx, c, s.WebEnv, s.QueryKey, fetched, pageSize)
+			if err != nil {
+				yield(nil, errors.Wrap(err, "Batched"))
+				return
+			}
+			if len(articles) == 0 {
+				return
+			}
+
+			fetched += len(articles)
+
+			if !yield(articles, nil) {
+				return
+			}
+		}
+	}
+}
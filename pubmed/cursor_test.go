@@ -0,0 +1,78 @@
+package pubmed_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/matryer/is"
+)
+
+// TestCursorReusesClientCacheAcrossOverlappingSearches proves that paging two
+// overlapping searches through the same Client only fetches the shared articles once,
+// which is the whole point of wiring EFetch's Cache into the history-paging path.
+func TestCursorReusesClientCacheAcrossOverlappingSearches(t *testing.T) {
+	is := is.New(t)
+
+	var efetchRequests int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "esearch.fcgi"):
+			w.Write([]byte(`{"esearchresult":{"count":"2","querykey":"1","webenv":"env","idlist":["1","2"]}}`))
+		case strings.HasSuffix(r.URL.Path, "efetch.fcgi"):
+			atomic.AddInt32(&efetchRequests, 1)
+
+			ids := strings.Split(r.Form.Get("id"), ",")
+			var body strings.Builder
+			body.WriteString(`<PubmedArticleSet>`)
+			for _, id := range ids {
+				body.WriteString(fmt.Sprintf(`<PubmedArticle><MedlineCitation><PMID>%s</PMID><Article><ArticleTitle>Title %s</ArticleTitle></Article></MedlineCitation></PubmedArticle>`, id, id))
+			}
+			body.WriteString(`</PubmedArticleSet>`)
+			w.Write([]byte(body.String()))
+		}
+	}))
+	defer srv.Close()
+
+	c := pubmed.NewClient("",
+		pubmed.WithRateLimit(1000),
+		pubmed.WithCache(pubmed.NewLRUCache(0), 0),
+	)
+	c.BaseURL = srv.URL
+
+	ctx := context.Background()
+
+	first, err := c.ESearch(ctx, pubmed.SearchParams{Term: "cardiology"})
+	is.NoErr(err)
+
+	var firstIDs []int
+	cursor := first.Cursor(ctx, c, 500)
+	for cursor.Next() {
+		firstIDs = append(firstIDs, cursor.Article().ID)
+	}
+	is.NoErr(cursor.Err())
+	is.Equal(len(firstIDs), 2)
+	is.Equal(int(atomic.LoadInt32(&efetchRequests)), 1)
+
+	// A second, overlapping search shares the same PMIDs; EFetch should serve them
+	// straight out of the client's cache instead of hitting efetch.fcgi again.
+	second, err := c.ESearch(ctx, pubmed.SearchParams{Term: "cardiology broader"})
+	is.NoErr(err)
+
+	var secondIDs []int
+	cursor = second.Cursor(ctx, c, 500)
+	for cursor.Next() {
+		secondIDs = append(secondIDs, cursor.Article().ID)
+	}
+	is.NoErr(cursor.Err())
+	is.Equal(len(secondIDs), 2)
+	is.Equal(int(atomic.LoadInt32(&efetchRequests)), 1) // Expected no new efetch.fcgi call
+}
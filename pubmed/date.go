@@ -0,0 +1,155 @@
+package pubmed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var monthAbbrev = map[string]time.Month{
+	"Jan": time.January, "Feb": time.February, "Mar": time.March, "Apr": time.April,
+	"May": time.May, "Jun": time.June, "Jul": time.July, "Aug": time.August,
+	"Sep": time.September, "Oct": time.October, "Nov": time.November, "Dec": time.December,
+}
+
+// seasonMonths maps the season/quarter names PubMed sometimes uses in place of a
+// month (e.g. "Winter 2019") to a representative month.
+var seasonMonths = map[string]time.Month{
+	"Spring": time.March, "Summer": time.June, "Autumn": time.September,
+	"Fall": time.September, "Winter": time.December,
+}
+
+// ResolvedPubDate resolves the article's best-known publication date. It first tries
+// the Journal's own PubDate, including 3-letter month abbreviations and free-text
+// season/quarter dates like "Winter 2019", and falls back to the earliest entry in
+// the article's history, preferring the "entrez" status recorded when the record was
+// first indexed.
+func (a PubMedArticle) ResolvedPubDate() (time.Time, error) {
+	if t, ok := parseJournalPubDate(a.PubDate); ok {
+		return t, nil
+	}
+
+	if t, ok := resolveHistoryDate(a.History); ok {
+		return t, nil
+	}
+
+	return time.Time{}, errors.Errorf("pubmed: no resolvable publication date for PMID %d", a.ID)
+}
+
+func parseJournalPubDate(d JournalPubDate) (time.Time, bool) {
+	if d.Year != "" {
+		year, err := strconv.Atoi(d.Year)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		month := time.January
+		if m, ok := monthAbbrev[d.Month]; ok {
+			month = m
+		}
+
+		day := 1
+		if n, err := strconv.Atoi(d.Day); err == nil {
+			day = n
+		}
+
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), true
+	}
+
+	if d.MedlineDate != "" {
+		return parseMedlineDate(d.MedlineDate)
+	}
+
+	return time.Time{}, false
+}
+
+// parseMedlineDate handles the free-text MedlineDate element used when a journal's
+// publication date doesn't fit Year/Month/Day, e.g. "Winter 2019" or "2019 Jan-Feb".
+func parseMedlineDate(s string) (time.Time, bool) {
+	month := time.January
+	year := ""
+
+	for _, f := range strings.Fields(s) {
+		if m, ok := seasonMonths[f]; ok {
+			month = m
+			continue
+		}
+		if len(f) >= 3 {
+			if m, ok := monthAbbrev[f[:3]]; ok {
+				month = m
+				continue
+			}
+		}
+		if _, err := strconv.Atoi(f); err == nil {
+			year = f
+		}
+	}
+
+	if year == "" {
+		return time.Time{}, false
+	}
+
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Date(y, month, 1, 0, 0, 0, 0, time.UTC), true
+}
+
+// resolveHistoryDate picks a date from an article's PubmedData>History entries,
+// preferring the one recorded with PubStatus "entrez" (the date the record first
+// entered PubMed) and otherwise falling back to the earliest date present.
+func resolveHistoryDate(history []HistoryDate) (time.Time, bool) {
+	var best time.Time
+	var found bool
+
+	for _, h := range history {
+		year, err := strconv.Atoi(h.Year)
+		if err != nil {
+			continue
+		}
+
+		month := time.January
+		if m, err := strconv.Atoi(h.Month); err == nil {
+			month = time.Month(m)
+		}
+
+		day := 1
+		if d, err := strconv.Atoi(h.Day); err == nil {
+			day = d
+		}
+
+		t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+		if h.PubStatus == "entrez" {
+			return t, true
+		}
+		if !found || t.Before(best) {
+			best = t
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// ResolvedAbstract concatenates all AbstractText sections in document order,
+// prefixing each with its label (e.g. "BACKGROUND:", "METHODS:") so structured
+// abstracts survive downstream rather than being truncated to their first section.
+func (a PubMedArticle) ResolvedAbstract() string {
+	var parts []string
+
+	for _, sec := range a.Abstract {
+		if sec.Key != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", strings.ToUpper(sec.Key), sec.Value))
+			continue
+		}
+		parts = append(parts, sec.Value)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
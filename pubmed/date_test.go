@@ -0,0 +1,68 @@
+package pubmed_test
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/matryer/is"
+)
+
+func loadArticleFixture(t *testing.T, name string) pubmed.PubMedArticle {
+	t.Helper()
+
+	xb, err := ioutil.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("cannot read %s: %v", name, err)
+	}
+
+	var set pubmed.PubMedArticleSet
+	if err := xml.Unmarshal(xb, &set); err != nil {
+		t.Fatalf("cannot unmarshal %s: %v", name, err)
+	}
+	if len(set.Articles) != 1 {
+		t.Fatalf("%s: expected 1 article, got %d", name, len(set.Articles))
+	}
+
+	return set.Articles[0]
+}
+
+func TestResolvedPubDateJournalDate(t *testing.T) {
+	is := is.New(t)
+	a := loadArticleFixture(t, "journal_date.xml")
+
+	got, err := a.ResolvedPubDate()
+	is.NoErr(err) // Error resolving journal pub date
+	is.Equal(got, time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC))
+}
+
+func TestResolvedPubDateSeasonDate(t *testing.T) {
+	is := is.New(t)
+	a := loadArticleFixture(t, "season_date.xml")
+
+	got, err := a.ResolvedPubDate()
+	is.NoErr(err) // Error resolving season pub date
+	is.Equal(got, time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestResolvedPubDateFallsBackToHistory(t *testing.T) {
+	is := is.New(t)
+	a := loadArticleFixture(t, "dateless_article.xml")
+
+	got, err := a.ResolvedPubDate()
+	is.NoErr(err) // Error falling back to history
+	is.Equal(got, time.Date(2018, time.May, 23, 0, 0, 0, 0, time.UTC))
+}
+
+func TestResolvedAbstractMultiSection(t *testing.T) {
+	is := is.New(t)
+	a := loadArticleFixture(t, "multi_section_abstract.xml")
+
+	got := a.ResolvedAbstract()
+	is.True(strings.Contains(got, "BACKGROUND: Heart disease is common.")) // Expected BACKGROUND section
+	is.True(strings.Contains(got, "METHODS: We did a thing."))             // Expected METHODS section
+	is.True(strings.Contains(got, "CONCLUSIONS: More study needed."))      // Expected CONCLUSIONS section
+}
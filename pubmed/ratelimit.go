@@ -0,0 +1,48 @@
+package pubmed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces out requests so they never exceed one per interval, matching
+// NCBI's published rate limits (3 requests/sec without an API key, 10/sec with one).
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(rps int) *rateLimiter {
+	if rps <= 0 {
+		rps = 3
+	}
+	return &rateLimiter{interval: time.Second / time.Duration(rps)}
+}
+
+// Wait blocks until the next request is allowed, or ctx is cancelled.
+func (r *rateLimiter) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	now := time.Now()
+	wait := r.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	r.next = now.Add(wait + r.interval)
+	r.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,40 @@
+package pubmed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestRateLimiterSpacesOutRequests(t *testing.T) {
+	is := is.New(t)
+
+	r := newRateLimiter(10) // 10 rps => 100ms between requests
+
+	ctx := context.Background()
+	start := time.Now()
+
+	is.NoErr(r.Wait(ctx))
+	is.NoErr(r.Wait(ctx))
+	is.NoErr(r.Wait(ctx))
+
+	elapsed := time.Since(start)
+	is.True(elapsed >= 200*time.Millisecond) // Expected two intervals' worth of spacing
+}
+
+func TestRateLimiterRespectsContextCancellation(t *testing.T) {
+	is := is.New(t)
+
+	r := newRateLimiter(1) // 1 rps => 1s between requests
+
+	ctx := context.Background()
+	is.NoErr(r.Wait(ctx))
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.Wait(cancelled)
+	is.True(err != nil) // Expected Wait to return the context's error once cancelled
+}
@@ -0,0 +1,194 @@
+// Package pubmedindex bulk-indexes pubmed.PubMedArticle records into Elasticsearch.
+package pubmedindex
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+
+	"github.com/8o8/articles/pubmed"
+)
+
+// IndexMapping is the index template applied by EnsureIndex on first use. pubDate is
+// mapped as a date so range queries work, keywords/meshHeadings as keyword so they
+// aggregate cleanly, and abstract as english-analyzed text for full-text search.
+const IndexMapping = `{
+	"mappings": {
+		"properties": {
+			"pubDate":      { "type": "date" },
+			"keywords":     { "type": "keyword" },
+			"meshHeadings": { "type": "keyword" },
+			"categories":   { "type": "keyword" },
+			"title":        { "type": "text" },
+			"abstract":     { "type": "text", "analyzer": "english" }
+		}
+	}
+}`
+
+// BulkIndexer wraps an elastic.BulkProcessor, batching documents up to BulkActions or
+// BulkSize before flushing, and reporting any failed items via OnError.
+type BulkIndexer struct {
+	Index         string
+	BulkActions   int
+	BulkSize      int
+	FlushInterval time.Duration
+	Workers       int
+	OnError       func(err error, failedItems []*elastic.BulkResponseItem)
+
+	processor *elastic.BulkProcessor
+}
+
+// Option configures a BulkIndexer built with NewBulkIndexer.
+type Option func(*BulkIndexer)
+
+// WithBulkActions sets how many queued documents trigger a flush.
+func WithBulkActions(n int) Option { return func(bi *BulkIndexer) { bi.BulkActions = n } }
+
+// WithBulkSize sets how many queued bytes trigger a flush.
+func WithBulkSize(bytes int) Option { return func(bi *BulkIndexer) { bi.BulkSize = bytes } }
+
+// WithFlushInterval sets how often queued documents are flushed regardless of size.
+func WithFlushInterval(d time.Duration) Option {
+	return func(bi *BulkIndexer) { bi.FlushInterval = d }
+}
+
+// WithWorkers sets how many concurrent bulk requests the processor may have in flight.
+func WithWorkers(n int) Option { return func(bi *BulkIndexer) { bi.Workers = n } }
+
+// WithOnError sets the callback invoked when a bulk request fails outright, or when
+// it succeeds but contains failed items.
+func WithOnError(f func(err error, failedItems []*elastic.BulkResponseItem)) Option {
+	return func(bi *BulkIndexer) { bi.OnError = f }
+}
+
+// NewBulkIndexer builds a BulkIndexer that writes to index, applying sane defaults
+// for any option not supplied.
+func NewBulkIndexer(ctx context.Context, client *elastic.Client, index string, opts ...Option) (*BulkIndexer, error) {
+	bi := &BulkIndexer{
+		Index:         index,
+		BulkActions:   500,
+		BulkSize:      5 << 20, // 5MB
+		FlushInterval: 5 * time.Second,
+		Workers:       2,
+	}
+
+	for _, opt := range opts {
+		opt(bi)
+	}
+
+	processor, err := client.BulkProcessor().
+		Name("pubmedindex").
+		Workers(bi.Workers).
+		BulkActions(bi.BulkActions).
+		BulkSize(bi.BulkSize).
+		FlushInterval(bi.FlushInterval).
+		After(bi.after).
+		Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewBulkIndexer")
+	}
+	bi.processor = processor
+
+	return bi, nil
+}
+
+func (bi *BulkIndexer) after(_ int64, _ []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
+	if bi.OnError == nil {
+		return
+	}
+	if err != nil {
+		bi.OnError(err, nil)
+		return
+	}
+	if response == nil {
+		return
+	}
+	if failed := response.Failed(); len(failed) > 0 {
+		bi.OnError(errors.Errorf("pubmedindex: %d item(s) failed", len(failed)), failed)
+	}
+}
+
+// IndexArticle queues a document for a: a scripted upsert that appends categories to
+// the document's existing categories list rather than clobbering it, which is what a
+// plain Doc overwrite would do on every re-index. Elasticsearch's Update API rejects a
+// request carrying both "doc" and "script", so the first-write document is supplied
+// via Upsert instead -- it's only used when no document exists yet, otherwise Script
+// runs against the existing one.
+func (bi *BulkIndexer) IndexArticle(a pubmed.PubMedArticle, categories []string) error {
+	id := strconv.Itoa(a.ID)
+
+	req := elastic.NewBulkUpdateRequest().
+		Index(bi.Index).
+		Id(id).
+		Upsert(mapDocument(a, categories)).
+		Script(appendCategoriesScript(categories))
+
+	bi.processor.Add(req)
+
+	return nil
+}
+
+// appendCategoriesScript appends each category to ctx._source.categories if it isn't
+// already present, initialising the field first -- an existing document may lack
+// categories entirely (e.g. one written without it), which would otherwise make the
+// append fail.
+func appendCategoriesScript(categories []string) *elastic.Script {
+	return elastic.NewScript(`
+		if (ctx._source.categories == null) { ctx._source.categories = [] }
+		for (cat in params.categories) {
+			if (!ctx._source.categories.contains(cat)) {
+				ctx._source.categories.add(cat)
+			}
+		}
+	`).Lang("painless").Params(map[string]interface{}{"categories": categories})
+}
+
+// mapDocument builds the document used when an article is indexed for the first
+// time, i.e. the Upsert value of a scripted upsert -- see IndexArticle.
+func mapDocument(a pubmed.PubMedArticle, categories []string) map[string]interface{} {
+	doc := map[string]interface{}{
+		"title":        a.Title,
+		"abstract":     a.ResolvedAbstract(),
+		"keywords":     a.KeywordList,
+		"meshHeadings": a.MeshHeadingList,
+		"categories":   categories,
+	}
+
+	if pubDate, err := a.ResolvedPubDate(); err == nil {
+		doc["pubDate"] = pubDate.Format(time.RFC3339)
+	}
+
+	return doc
+}
+
+// EnsureIndex creates index name with mappingJSON as its body if it doesn't already
+// exist, so callers don't have to manage index templates out of band.
+func EnsureIndex(ctx context.Context, client *elastic.Client, name, mappingJSON string) error {
+	exists, err := client.IndexExists(name).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "EnsureIndex")
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = client.CreateIndex(name).BodyString(mappingJSON).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "EnsureIndex")
+	}
+
+	return nil
+}
+
+// Flush blocks until all queued documents have been sent.
+func (bi *BulkIndexer) Flush() error {
+	return bi.processor.Flush()
+}
+
+// Close flushes and stops the underlying BulkProcessor.
+func (bi *BulkIndexer) Close() error {
+	return bi.processor.Close()
+}
@@ -0,0 +1,55 @@
+package pubmedindex
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/matryer/is"
+)
+
+func TestMapDocumentShape(t *testing.T) {
+	is := is.New(t)
+
+	a := pubmed.PubMedArticle{
+		ID:              1,
+		Title:           "A trial",
+		KeywordList:     []string{"heart"},
+		MeshHeadingList: []string{"Cardiology"},
+	}
+
+	doc := mapDocument(a, []string{"cardiology"})
+
+	is.Equal(doc["title"], "A trial")
+	is.Equal(doc["abstract"], a.ResolvedAbstract())
+	is.Equal(doc["keywords"], []string{"heart"})
+	is.Equal(doc["meshHeadings"], []string{"Cardiology"})
+	is.Equal(doc["categories"], []string{"cardiology"})
+	_, hasPubDate := doc["pubDate"]
+	is.True(!hasPubDate) // Expected no pubDate when the article has none to resolve
+}
+
+// appendCategoriesScript is the fix this request was specifically about: a plain Doc
+// overwrite would clobber an existing document's categories on every re-index, so
+// IndexArticle instead scripts an append. Assert the script actually guards against a
+// missing categories field and appends rather than replaces.
+func TestAppendCategoriesScriptAppendsRatherThanClobbers(t *testing.T) {
+	is := is.New(t)
+
+	script := appendCategoriesScript([]string{"cardiology", "oncology"})
+
+	src, err := script.Source()
+	is.NoErr(err)
+	source, ok := src.(map[string]interface{})
+	is.True(ok) // Expected Source() to return the script's source map
+
+	body, ok := source["source"].(*json.RawMessage)
+	is.True(ok)
+	is.True(strings.Contains(string(*body), "ctx._source.categories == null"))  // Expected a guard for a missing categories field
+	is.True(strings.Contains(string(*body), "ctx._source.categories.add(cat)")) // Expected an append, not an overwrite
+
+	params, ok := source["params"].(map[string]interface{})
+	is.True(ok)
+	is.Equal(params["categories"], []string{"cardiology", "oncology"})
+}
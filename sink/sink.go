@@ -0,0 +1,143 @@
+// Package sink decouples the pubmed search/fetch pipeline from any one storage
+// backend, so articles can be routed to Elasticsearch, stdout, a file, or fanned out
+// to several of those at once.
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/8o8/articles/pubmedindex"
+)
+
+// Sink is a destination for fetched PubMed articles.
+type Sink interface {
+	Write(ctx context.Context, articles []pubmed.PubMedArticle, categories []string) error
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+// ElasticSink writes articles to Elasticsearch via a pubmedindex.BulkIndexer.
+type ElasticSink struct {
+	Indexer *pubmedindex.BulkIndexer
+}
+
+// NewElasticSink returns an ElasticSink that queues writes on indexer.
+func NewElasticSink(indexer *pubmedindex.BulkIndexer) *ElasticSink {
+	return &ElasticSink{Indexer: indexer}
+}
+
+// Write queues each article for indexing.
+func (s *ElasticSink) Write(_ context.Context, articles []pubmed.PubMedArticle, categories []string) error {
+	for _, a := range articles {
+		if err := s.Indexer.IndexArticle(a, categories); err != nil {
+			return errors.Wrap(err, "ElasticSink.Write")
+		}
+	}
+	return nil
+}
+
+// Flush blocks until every queued document has been sent.
+func (s *ElasticSink) Flush(_ context.Context) error { return s.Indexer.Flush() }
+
+// Close flushes and stops the underlying indexer.
+func (s *ElasticSink) Close() error { return s.Indexer.Close() }
+
+// JSONLinesSink writes one JSON-encoded article per line to w, useful for dry-running
+// a query or archiving a corpus without touching Elasticsearch.
+type JSONLinesSink struct {
+	enc *json.Encoder
+}
+
+// NewJSONLinesSink returns a JSONLinesSink writing to w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{enc: json.NewEncoder(w)}
+}
+
+// Write encodes each article as a line of JSON.
+func (s *JSONLinesSink) Write(_ context.Context, articles []pubmed.PubMedArticle, _ []string) error {
+	for _, a := range articles {
+		if err := s.enc.Encode(a); err != nil {
+			return errors.Wrap(err, "JSONLinesSink.Write")
+		}
+	}
+	return nil
+}
+
+// Flush is a no-op; JSONLinesSink writes synchronously.
+func (s *JSONLinesSink) Flush(_ context.Context) error { return nil }
+
+// Close is a no-op; JSONLinesSink does not own its io.Writer.
+func (s *JSONLinesSink) Close() error { return nil }
+
+// StdoutSink prints a one-line summary of each article to w, handy for smoke-testing
+// a query's shape before wiring up a real sink.
+type StdoutSink struct {
+	w io.Writer
+}
+
+// NewStdoutSink returns a StdoutSink writing to w.
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	return &StdoutSink{w: w}
+}
+
+// Write prints the PMID, title and categories of each article, one per line.
+func (s *StdoutSink) Write(_ context.Context, articles []pubmed.PubMedArticle, categories []string) error {
+	for _, a := range articles {
+		if _, err := fmt.Fprintf(s.w, "%d\t%s\t%v\n", a.ID, a.Title, categories); err != nil {
+			return errors.Wrap(err, "StdoutSink.Write")
+		}
+	}
+	return nil
+}
+
+// Flush is a no-op; StdoutSink writes synchronously.
+func (s *StdoutSink) Flush(_ context.Context) error { return nil }
+
+// Close is a no-op; StdoutSink does not own its io.Writer.
+func (s *StdoutSink) Close() error { return nil }
+
+// MultiSink fans out writes to every underlying Sink, stopping at the first error.
+type MultiSink struct {
+	Sinks []Sink
+}
+
+// NewMultiSink returns a MultiSink fanning out to sinks in order.
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{Sinks: sinks}
+}
+
+// Write fans out to every sink, stopping at the first error.
+func (s *MultiSink) Write(ctx context.Context, articles []pubmed.PubMedArticle, categories []string) error {
+	for _, sk := range s.Sinks {
+		if err := sk.Write(ctx, articles, categories); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes every sink, stopping at the first error.
+func (s *MultiSink) Flush(ctx context.Context) error {
+	for _, sk := range s.Sinks {
+		if err := sk.Flush(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes every sink, stopping at the first error.
+func (s *MultiSink) Close() error {
+	for _, sk := range s.Sinks {
+		if err := sk.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package sink_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/8o8/articles/pubmed"
+	"github.com/8o8/articles/sink"
+	"github.com/matryer/is"
+)
+
+func TestJSONLinesSinkWritesOneArticlePerLine(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	s := sink.NewJSONLinesSink(&buf)
+
+	err := s.Write(context.Background(), []pubmed.PubMedArticle{
+		{ID: 1, Title: "one"},
+		{ID: 2, Title: "two"},
+	}, []string{"cardiology"})
+	is.NoErr(err)
+
+	lines := bytes.Split(bytes.TrimRight(buf.Bytes(), "\n"), []byte("\n"))
+	is.Equal(len(lines), 2) // one line per article
+
+	var a pubmed.PubMedArticle
+	is.NoErr(json.Unmarshal(lines[0], &a))
+	is.Equal(a.ID, 1)
+	is.Equal(a.Title, "one")
+}
+
+// countingSink records how many times Write/Flush/Close were called, optionally
+// failing the Write call numbered failOn (1-indexed; 0 means never fail). Flush and
+// Close never fail.
+type countingSink struct {
+	writes, flushes, closes int
+	failOn                  int
+}
+
+func (s *countingSink) Write(context.Context, []pubmed.PubMedArticle, []string) error {
+	s.writes++
+	if s.failOn != 0 && s.writes == s.failOn {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func (s *countingSink) Flush(context.Context) error { s.flushes++; return nil }
+func (s *countingSink) Close() error                { s.closes++; return nil }
+
+func TestMultiSinkFansOutToEverySink(t *testing.T) {
+	is := is.New(t)
+	a, b := &countingSink{}, &countingSink{}
+	m := sink.NewMultiSink(a, b)
+
+	is.NoErr(m.Write(context.Background(), []pubmed.PubMedArticle{{ID: 1}}, nil))
+	is.NoErr(m.Flush(context.Background()))
+	is.NoErr(m.Close())
+
+	is.Equal(a.writes, 1)
+	is.Equal(b.writes, 1)
+	is.Equal(a.flushes, 1)
+	is.Equal(b.flushes, 1)
+	is.Equal(a.closes, 1)
+	is.Equal(b.closes, 1)
+}
+
+func TestMultiSinkStopsAtFirstError(t *testing.T) {
+	is := is.New(t)
+	failing, never := &countingSink{failOn: 1}, &countingSink{}
+	m := sink.NewMultiSink(failing, never)
+
+	err := m.Write(context.Background(), []pubmed.PubMedArticle{{ID: 1}}, nil)
+	is.True(err != nil) // Expected the first sink's error to propagate
+
+	is.Equal(failing.writes, 1)
+	is.Equal(never.writes, 0) // Expected the second sink to never be reached
+}